@@ -18,6 +18,10 @@ var maxReconnectTimeout = time.Second * 60
 // ErrClosedManually to exit for loop
 var ErrClosedManually = errors.New("connection closed manually")
 
+// ErrMaxReconnectAttempts is returned once WithMaxReconnectAttempts is
+// exceeded and reconnecting is abandoned.
+var ErrMaxReconnectAttempts = errors.New("gonet: max reconnect attempts exceeded")
+
 type reconnectWaitGroup struct {
 	sync.WaitGroup
 	err error
@@ -32,41 +36,140 @@ type AutoReconnectConn struct {
 	mu   sync.Mutex
 	wg   *reconnectWaitGroup
 
+	// gaveUp is set once WithMaxReconnectAttempts is exceeded, so that later
+	// Read/Write/keepalive-triggered reconnects fail fast with
+	// ErrMaxReconnectAttempts instead of redialing from zero again.
+	gaveUp bool
+
 	OnConnected func(net.Conn) error
+
+	keepAliveInterval time.Duration
+	keepAliveTimeout  time.Duration
+	keepAliveProbe    func(net.Conn) error
+
+	backoff              BackoffPolicy
+	maxReconnectAttempts int
+	onDisconnected       func(error)
+	onReconnecting       func(attempt int, delay time.Duration)
+	onReconnectFailed    func(error)
+
+	writeBuf *writeBuffer
+
+	network     string
+	addr        string
+	dialer      Dialer
+	sniFallback []string
 }
 
-func newConn(conn net.Conn, connOpts ...ConnOption) *AutoReconnectConn {
-	c := &AutoReconnectConn{Conn: conn}
+func newConn(conn net.Conn, network, addr string, connOpts ...ConnOption) *AutoReconnectConn {
+	c := &AutoReconnectConn{Conn: conn, network: network, addr: addr}
 	for _, opt := range connOpts {
 		opt(c)
 	}
 	c.ctx, c.quit = context.WithCancel(context.Background())
+	if c.keepAliveProbe != nil && c.keepAliveInterval > 0 {
+		go c.keepAliveLoop()
+	}
 	return c
 }
 
+// currentConn returns the live underlying net.Conn. It must be used instead
+// of reading the embedded Conn field directly, since reconnect() swaps it
+// from a background goroutine (keepAliveLoop, or a buffered Write's async
+// reconnect) concurrently with callers' own Read/Write.
+func (c *AutoReconnectConn) currentConn() net.Conn {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.Conn
+}
+
+func (c *AutoReconnectConn) setConn(conn net.Conn) {
+	c.mu.Lock()
+	c.Conn = conn
+	c.mu.Unlock()
+}
+
+// giveUp marks the connection as permanently abandoned after
+// WithMaxReconnectAttempts is exceeded, so that reconnect() short-circuits on
+// every future call instead of redialing the configured number of attempts
+// all over again.
+func (c *AutoReconnectConn) giveUp() {
+	c.mu.Lock()
+	c.gaveUp = true
+	c.mu.Unlock()
+}
+
+// ResetReconnectAttempts clears the "gave up" state left behind once
+// WithMaxReconnectAttempts was exceeded, letting the next Read, Write, or
+// keepalive tick attempt to reconnect again instead of immediately returning
+// ErrMaxReconnectAttempts.
+func (c *AutoReconnectConn) ResetReconnectAttempts() {
+	c.mu.Lock()
+	c.gaveUp = false
+	c.mu.Unlock()
+}
+
 // Close implement net.Conn interface
 func (c *AutoReconnectConn) Close() error {
 	c.quit()
-	return c.Conn.Close()
+	return c.currentConn().Close()
 }
 
 func (c *AutoReconnectConn) Write(b []byte) (int, error) {
+	if c.writeBuf == nil {
+		return c.writeDirect(b)
+	}
+
+	// Checking c.wg and enqueuing must happen under the same lock that
+	// drainWriteBuffer uses to decide the buffer is fully drained -- otherwise
+	// a write could land in the buffer just after drainWriteBuffer's last
+	// "is it empty" check and sit there, unflushed, until the next reconnect
+	// even though the connection is already healthy again.
+	c.mu.Lock()
+	if c.wg != nil {
+		n, err := c.enqueueWrite(b)
+		c.mu.Unlock()
+		return n, err
+	}
+	c.mu.Unlock()
+
+	log.Printf("Prefer to write %s", b)
+	n, err := c.currentConn().Write(b)
+	var netErr net.Error
+	if err == io.EOF || (errors.As(err, &netErr) && !netErr.Temporary()) {
+		go c.reconnect(err)
+		return c.enqueueWrite(b)
+	}
+	return n, err
+}
+
+// writeDirect is the unbuffered Write path: it blocks until any in-progress
+// reconnect settles and retries the write after every reconnect, as before
+// WithWriteBuffer existed.
+func (c *AutoReconnectConn) writeDirect(b []byte) (int, error) {
 	log.Printf("Prefer to write %s", b)
-	n, err := c.Conn.Write(b)
+	n, err := c.currentConn().Write(b)
 	isReconnected, err := c.reconnectIfNeeded(err)
 	if err != nil {
 		return 0, err
 	}
 	if isReconnected {
-		return c.Write(b)
+		return c.writeDirect(b)
 	}
 	return n, err
 }
 
+func (c *AutoReconnectConn) enqueueWrite(b []byte) (int, error) {
+	if err := c.writeBuf.enqueue(b); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
 // Read implement net.Conn interface
 // Reconnect if read io.EOF or read non temporary net.Error
 func (c *AutoReconnectConn) Read(b []byte) (int, error) {
-	n, err := c.Conn.Read(b)
+	n, err := c.currentConn().Read(b)
 	isReconnected, err := c.reconnectIfNeeded(err)
 	if err != nil {
 		return 0, err
@@ -84,7 +187,8 @@ func (c *AutoReconnectConn) reconnectIfNeeded(err error) (bool, error) {
 
 	var netErr net.Error
 	if err == io.EOF || (errors.As(err, &netErr) && !netErr.Temporary()) {
-		err = c.reconnect()
+		cause := err
+		err = c.reconnect(cause)
 	} else {
 		return false, err
 	}
@@ -92,10 +196,15 @@ func (c *AutoReconnectConn) reconnectIfNeeded(err error) (bool, error) {
 	return true, err
 }
 
-// The returns error is either nil or io.EOF
-func (c *AutoReconnectConn) reconnect() error {
+// The returns error is either nil, ErrClosedManually, ErrMaxReconnectAttempts
+// or the error OnConnected returned
+func (c *AutoReconnectConn) reconnect(cause error) error {
 
 	c.mu.Lock()
+	if c.gaveUp {
+		c.mu.Unlock()
+		return ErrMaxReconnectAttempts
+	}
 	if c.wg != nil {
 		wg := c.wg
 		c.mu.Unlock()
@@ -107,9 +216,20 @@ func (c *AutoReconnectConn) reconnect() error {
 	c.wg.Add(1)
 	c.mu.Unlock()
 
-	// do reconnect
-	raddr := c.Conn.RemoteAddr()
-	timeout := time.Second
+	if c.onDisconnected != nil {
+		c.onDisconnected(cause)
+	}
+
+	backoff := c.backoff
+	if backoff == nil {
+		backoff = defaultBackoff
+	}
+	dial := c.dialer
+	if dial == nil {
+		dial = defaultDialer
+	}
+
+	attempt := 0
 
 reconnect:
 	for {
@@ -120,49 +240,104 @@ reconnect:
 		default:
 		}
 
-		log.Printf("Reconnect timeout: %v", timeout)
-		conn, err := (&net.Dialer{}).DialContext(c.ctx, raddr.Network(), raddr.String())
+		if c.maxReconnectAttempts > 0 && attempt >= c.maxReconnectAttempts {
+			c.wg.err = ErrMaxReconnectAttempts
+			c.giveUp()
+			if c.onReconnectFailed != nil {
+				c.onReconnectFailed(c.wg.err)
+			}
+			break reconnect
+		}
+
+		conn, err := dial(c.ctx, c.network, c.addr)
 		if err != nil {
-			timeout *= 2
-			if timeout > maxReconnectTimeout {
-				timeout = maxReconnectTimeout
+			delay, retry := backoff.NextDelay(attempt, err)
+			attempt++
+			if !retry {
+				c.wg.err = ErrMaxReconnectAttempts
+				c.giveUp()
+				if c.onReconnectFailed != nil {
+					c.onReconnectFailed(c.wg.err)
+				}
+				break reconnect
+			}
+
+			log.Printf("Reconnect attempt %d failed, retry in %v", attempt, delay)
+			if c.onReconnecting != nil {
+				c.onReconnecting(attempt, delay)
 			}
 			select {
-			case <-time.After(timeout):
+			case <-time.After(delay):
 				continue
 			case <-c.ctx.Done():
 				c.wg.err = ErrClosedManually
 				break reconnect
 			}
 		}
-		c.Conn = conn
+		c.setConn(conn)
 
 		// on connected custom event callback
 		if c.OnConnected != nil {
-			c.wg.err = c.OnConnected(c.Conn)
+			c.wg.err = c.OnConnected(conn)
+		}
+
+		if c.wg.err == nil && c.writeBuf != nil {
+			return c.drainWriteBuffer(conn)
 		}
 
 		break reconnect
 	}
 
+	return c.release()
+}
+
+// release clears the in-flight reconnect state and wakes any goroutines
+// blocked on it in reconnect(), returning the error this attempt settled on.
+func (c *AutoReconnectConn) release() error {
 	c.mu.Lock()
 	wg := c.wg
 	c.wg = nil
 	c.mu.Unlock()
 
-	// release
 	wg.Done()
-
 	return wg.err
 }
 
+// drainWriteBuffer flushes every write queued while reconnecting was in
+// progress, looping until a pass leaves the buffer empty. That emptiness
+// check happens under c.mu -- the same lock Write uses to decide whether to
+// buffer -- so the in-flight state is only cleared once it's certain no write
+// snuck into the buffer in between; otherwise that write would sit unflushed
+// until the next reconnect even though the connection is already healthy.
+func (c *AutoReconnectConn) drainWriteBuffer(conn net.Conn) error {
+	for {
+		if ferr := c.writeBuf.flush(func(p []byte) error {
+			_, err := conn.Write(p)
+			return err
+		}); ferr != nil {
+			log.Printf("Flush buffered writes failed, %v", ferr)
+			return c.release()
+		}
+
+		c.mu.Lock()
+		if c.writeBuf.isEmpty() {
+			wg := c.wg
+			c.wg = nil
+			c.mu.Unlock()
+			wg.Done()
+			return wg.err
+		}
+		c.mu.Unlock()
+	}
+}
+
 // DialAutoReconnectContext wrap net.DialContext
 func DialAutoReconnectContext(ctx context.Context, network string, address string, connOpts ...ConnOption) (net.Conn, error) {
 	conn, err := (&net.Dialer{}).DialContext(ctx, network, address)
 	if err != nil {
 		return nil, err
 	}
-	autoConn := newConn(conn, connOpts...)
+	autoConn := newConn(conn, network, address, connOpts...)
 
 	if autoConn.OnConnected != nil {
 		if err := autoConn.OnConnected(conn); err != nil {
@@ -0,0 +1,50 @@
+package gonet
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func discard(conn net.Conn) {
+	buf := make([]byte, 4096)
+	for {
+		if _, err := conn.Read(buf); err != nil {
+			return
+		}
+	}
+}
+
+// TestKeepAliveConcurrentWrite reproduces a data race (caught by `go test
+// -race`) between keepAliveLoop's background reconnects, which swap the
+// embedded Conn field, and a caller's own concurrent Writes reading it.
+func TestKeepAliveConcurrentWrite(t *testing.T) {
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		client, server := net.Pipe()
+		go discard(server)
+		return client, nil
+	}
+
+	client, server := net.Pipe()
+	go discard(server)
+
+	conn := newConn(client, "tcp", "fake",
+		WithDialer(dial),
+		WithKeepAlive(time.Millisecond, 10*time.Millisecond, func(net.Conn) error {
+			return errors.New("force reconnect")
+		}),
+	)
+	defer conn.Close()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		deadline := time.Now().Add(50 * time.Millisecond)
+		for time.Now().Before(deadline) {
+			conn.Write([]byte("ping"))
+		}
+	}()
+	<-done
+}
@@ -0,0 +1,140 @@
+package gonet
+
+import (
+	"math/rand"
+	"time"
+)
+
+// BackoffPolicy decides how long to wait before the next reconnect attempt.
+// NextDelay is called with the current attempt number (starting at 0) and
+// the error from the most recent failed attempt, and returns the delay to
+// wait plus whether another attempt should be made at all.
+type BackoffPolicy interface {
+	NextDelay(attempt int, lastErr error) (delay time.Duration, retry bool)
+}
+
+// ConstantBackoff waits a fixed Delay between every attempt.
+type ConstantBackoff struct {
+	Delay time.Duration
+}
+
+// NextDelay implements BackoffPolicy.
+func (b ConstantBackoff) NextDelay(attempt int, lastErr error) (time.Duration, bool) {
+	return b.Delay, true
+}
+
+// ExponentialBackoff doubles the delay starting from Base, capped at Max. If
+// Jitter is true it applies "full jitter"
+// (https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/),
+// picking a random delay between 0 and the capped exponential value.
+type ExponentialBackoff struct {
+	Base   time.Duration
+	Max    time.Duration
+	Jitter bool
+}
+
+// NextDelay implements BackoffPolicy.
+func (b ExponentialBackoff) NextDelay(attempt int, lastErr error) (time.Duration, bool) {
+	delay := b.Base << uint(attempt)
+	if delay <= 0 || delay > b.Max {
+		delay = b.Max
+	}
+	if b.Jitter {
+		delay = time.Duration(rand.Int63n(int64(delay) + 1))
+	}
+	return delay, true
+}
+
+// DecorrelatedJitterBackoff implements the "decorrelated jitter" strategy:
+// each delay is chosen uniformly between Base and three times the previous
+// delay, capped at Max. The zero value is ready to use: a Base or Max <= 0
+// falls back to the same 1s/60s defaults as defaultBackoff, rather than
+// busy-looping with a zero delay.
+type DecorrelatedJitterBackoff struct {
+	Base time.Duration
+	Max  time.Duration
+
+	prev time.Duration
+}
+
+// NextDelay implements BackoffPolicy.
+func (b *DecorrelatedJitterBackoff) NextDelay(attempt int, lastErr error) (time.Duration, bool) {
+	base := b.Base
+	if base <= 0 {
+		base = time.Second
+	}
+	max := b.Max
+	if max <= 0 {
+		max = maxReconnectTimeout
+	}
+
+	prev := b.prev
+	if prev <= 0 {
+		prev = base
+	}
+	upper := prev * 3
+	if upper > max {
+		upper = max
+	}
+	if upper <= base {
+		b.prev = base
+		return base, true
+	}
+	delay := base + time.Duration(rand.Int63n(int64(upper-base)))
+	b.prev = delay
+	return delay, true
+}
+
+// defaultBackoff keeps the package's original exponential doubling capped at
+// 60s and is used when WithBackoff is not supplied. Its first wait is 1s; the
+// original reconnect loop's first wait was 2s.
+var defaultBackoff BackoffPolicy = ExponentialBackoff{Base: time.Second, Max: maxReconnectTimeout}
+
+// WithBackoff sets the policy used to space out reconnect attempts. Without
+// this option the connection falls back to exponential doubling capped at
+// 60s, matching the package's original behaviour.
+func WithBackoff(policy BackoffPolicy) ConnOption {
+	return func(conn *AutoReconnectConn) {
+		conn.backoff = policy
+	}
+}
+
+// WithMaxReconnectAttempts stops reconnecting after n consecutive failed
+// dial attempts, surfacing ErrMaxReconnectAttempts from Read/Write instead of
+// retrying forever. Once the limit is hit the connection stays given up on
+// permanently -- every later Read, Write, or keepalive tick keeps returning
+// ErrMaxReconnectAttempts without dialing again -- until
+// AutoReconnectConn.ResetReconnectAttempts is called. n <= 0 (the default)
+// means unlimited attempts.
+func WithMaxReconnectAttempts(n int) ConnOption {
+	return func(conn *AutoReconnectConn) {
+		conn.maxReconnectAttempts = n
+	}
+}
+
+// WithOnDisconnected registers a callback invoked once, with the error that
+// triggered it, whenever the connection is detected as broken and a
+// reconnect sequence begins.
+func WithOnDisconnected(fn func(error)) ConnOption {
+	return func(conn *AutoReconnectConn) {
+		conn.onDisconnected = fn
+	}
+}
+
+// WithOnReconnecting registers a callback invoked before each reconnect
+// attempt's backoff wait, with the attempt number and the delay about to be
+// slept. Useful for metrics and structured logging.
+func WithOnReconnecting(fn func(attempt int, delay time.Duration)) ConnOption {
+	return func(conn *AutoReconnectConn) {
+		conn.onReconnecting = fn
+	}
+}
+
+// WithOnReconnectFailed registers a callback invoked once reconnecting is
+// abandoned after WithMaxReconnectAttempts is exceeded, letting callers
+// implement circuit-breaker behavior on top of AutoReconnectConn.
+func WithOnReconnectFailed(fn func(error)) ConnOption {
+	return func(conn *AutoReconnectConn) {
+		conn.onReconnectFailed = fn
+	}
+}
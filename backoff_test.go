@@ -0,0 +1,67 @@
+package gonet
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestMaxReconnectAttemptsSticky verifies that once WithMaxReconnectAttempts
+// is exceeded, the connection stays given up on: further reconnect() calls
+// must not dial again until ResetReconnectAttempts is called.
+func TestMaxReconnectAttemptsSticky(t *testing.T) {
+	var dials int32
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		atomic.AddInt32(&dials, 1)
+		return nil, errors.New("connection refused")
+	}
+
+	client, _ := net.Pipe()
+	conn := newConn(client, "tcp", "fake",
+		WithDialer(dial),
+		WithBackoff(ConstantBackoff{Delay: time.Millisecond}),
+		WithMaxReconnectAttempts(2),
+	)
+	defer conn.Close()
+
+	err := conn.reconnect(errors.New("initial failure"))
+	if !errors.Is(err, ErrMaxReconnectAttempts) {
+		t.Fatalf("reconnect() = %v, want ErrMaxReconnectAttempts", err)
+	}
+	afterGiveUp := atomic.LoadInt32(&dials)
+	if afterGiveUp != 2 {
+		t.Fatalf("dialed %d times before giving up, want 2", afterGiveUp)
+	}
+
+	err = conn.reconnect(errors.New("still broken"))
+	if !errors.Is(err, ErrMaxReconnectAttempts) {
+		t.Fatalf("reconnect() after giving up = %v, want ErrMaxReconnectAttempts", err)
+	}
+	if got := atomic.LoadInt32(&dials); got != afterGiveUp {
+		t.Fatalf("reconnect() redialed after giving up: %d dials, want %d", got, afterGiveUp)
+	}
+
+	conn.ResetReconnectAttempts()
+	_ = conn.reconnect(errors.New("retry after reset"))
+	if got := atomic.LoadInt32(&dials); got <= afterGiveUp {
+		t.Fatalf("reconnect() did not redial after ResetReconnectAttempts: %d dials, want > %d", got, afterGiveUp)
+	}
+}
+
+// TestDecorrelatedJitterBackoffZeroValue verifies that the documented
+// "zero value is ready to use" claim holds: with Base and Max left at 0, it
+// must not busy-loop with a zero delay.
+func TestDecorrelatedJitterBackoffZeroValue(t *testing.T) {
+	var b DecorrelatedJitterBackoff
+
+	delay, retry := b.NextDelay(0, errors.New("dial failed"))
+	if !retry {
+		t.Fatalf("NextDelay() retry = false, want true")
+	}
+	if delay <= 0 {
+		t.Fatalf("NextDelay() delay = %v, want > 0 for the zero value", delay)
+	}
+}
@@ -0,0 +1,212 @@
+package frame
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// ErrFrameTooLarge is returned by a Codec's ReadFrame when a frame's encoded
+// length exceeds the codec's MaxFrameSize.
+var ErrFrameTooLarge = errors.New("frame: frame exceeds max size")
+
+// ErrFrameContainsDelimiter is returned by NewlineCodec.WriteFrame when the
+// payload itself contains the delimiter byte and so cannot be framed
+// unambiguously.
+var ErrFrameContainsDelimiter = errors.New("frame: payload contains delimiter")
+
+// defaultMaxFrameSize is used by every Codec whose MaxFrameSize is left at
+// its zero value, so the easiest way to construct a Codec doesn't also leave
+// ReadFrame willing to allocate an attacker-chosen amount of memory.
+const defaultMaxFrameSize = 4 << 20 // 4 MiB
+
+// readBounded reads exactly n bytes from r, rejecting n > max with
+// ErrFrameTooLarge before ever allocating a buffer for it.
+func readBounded(r *bufio.Reader, n, max uint64) ([]byte, error) {
+	if n > max {
+		return nil, ErrFrameTooLarge
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// Codec frames outgoing payloads and parses them back out of a byte stream.
+type Codec interface {
+	// WriteFrame encodes p as a single frame and writes it to w.
+	WriteFrame(w io.Writer, p []byte) error
+	// ReadFrame reads and decodes the next frame from r.
+	ReadFrame(r *bufio.Reader) ([]byte, error)
+}
+
+// NewlineCodec frames payloads as a line terminated by '\n', matching the
+// bufio.Scanner/ReadLine framing both example programs implemented by hand.
+// MaxFrameSize, if non-zero, bounds how many bytes ReadFrame will buffer
+// while looking for the delimiter; it defaults to defaultMaxFrameSize.
+type NewlineCodec struct {
+	MaxFrameSize int
+}
+
+// WriteFrame implements Codec.
+func (NewlineCodec) WriteFrame(w io.Writer, p []byte) error {
+	if bytes.IndexByte(p, '\n') != -1 {
+		return ErrFrameContainsDelimiter
+	}
+	_, err := w.Write(append(append([]byte(nil), p...), '\n'))
+	return err
+}
+
+// ReadFrame implements Codec.
+func (c NewlineCodec) ReadFrame(r *bufio.Reader) ([]byte, error) {
+	max := c.MaxFrameSize
+	if max <= 0 {
+		max = defaultMaxFrameSize
+	}
+
+	buf := make([]byte, 0, 64)
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if b == '\n' {
+			return buf, nil
+		}
+		if len(buf) >= max {
+			return nil, ErrFrameTooLarge
+		}
+		buf = append(buf, b)
+	}
+}
+
+// LengthPrefixCodec frames payloads with a 4-byte big-endian length header.
+// MaxFrameSize, if non-zero, bounds the length ReadFrame will accept before
+// allocating a buffer for it.
+type LengthPrefixCodec struct {
+	MaxFrameSize uint32
+}
+
+// WriteFrame implements Codec.
+func (c LengthPrefixCodec) WriteFrame(w io.Writer, p []byte) error {
+	var hdr [4]byte
+	binary.BigEndian.PutUint32(hdr[:], uint32(len(p)))
+	if _, err := w.Write(hdr[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(p)
+	return err
+}
+
+// ReadFrame implements Codec.
+func (c LengthPrefixCodec) ReadFrame(r *bufio.Reader) ([]byte, error) {
+	var hdr [4]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return nil, err
+	}
+	max := uint64(c.MaxFrameSize)
+	if max == 0 {
+		max = defaultMaxFrameSize
+	}
+	return readBounded(r, uint64(binary.BigEndian.Uint32(hdr[:])), max)
+}
+
+// VarintLengthPrefixCodec frames payloads with a protobuf-style unsigned
+// varint length header, saving a few bytes over LengthPrefixCodec for small
+// payloads. MaxFrameSize, if non-zero, bounds the length ReadFrame will
+// accept before allocating a buffer for it.
+type VarintLengthPrefixCodec struct {
+	MaxFrameSize uint64
+}
+
+// WriteFrame implements Codec.
+func (c VarintLengthPrefixCodec) WriteFrame(w io.Writer, p []byte) error {
+	var hdr [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(hdr[:], uint64(len(p)))
+	if _, err := w.Write(hdr[:n]); err != nil {
+		return err
+	}
+	_, err := w.Write(p)
+	return err
+}
+
+// ReadFrame implements Codec.
+func (c VarintLengthPrefixCodec) ReadFrame(r *bufio.Reader) ([]byte, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	max := c.MaxFrameSize
+	if max == 0 {
+		max = defaultMaxFrameSize
+	}
+	return readBounded(r, n, max)
+}
+
+// NetstringCodec frames payloads as netstrings: "<length>:<data>,"
+// (https://en.wikipedia.org/wiki/Netstring). MaxFrameSize, if non-zero,
+// bounds the length ReadFrame will accept before allocating a buffer for it.
+type NetstringCodec struct {
+	MaxFrameSize int
+}
+
+// WriteFrame implements Codec.
+func (c NetstringCodec) WriteFrame(w io.Writer, p []byte) error {
+	if _, err := fmt.Fprintf(w, "%d:", len(p)); err != nil {
+		return err
+	}
+	if _, err := w.Write(p); err != nil {
+		return err
+	}
+	_, err := w.Write([]byte{','})
+	return err
+}
+
+// maxNetstringLenDigits bounds how many digits ReadFrame will scan looking
+// for the ':' that ends a netstring's length prefix, so a peer that never
+// sends one can't make it buffer an unbounded amount of data. It comfortably
+// fits the longest possible decimal uint64 (20 digits).
+const maxNetstringLenDigits = 20
+
+// ReadFrame implements Codec.
+func (c NetstringCodec) ReadFrame(r *bufio.Reader) ([]byte, error) {
+	var lenBuf []byte
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if b == ':' {
+			break
+		}
+		if len(lenBuf) >= maxNetstringLenDigits {
+			return nil, ErrFrameTooLarge
+		}
+		lenBuf = append(lenBuf, b)
+	}
+	n, err := strconv.Atoi(string(lenBuf))
+	if err != nil {
+		return nil, fmt.Errorf("frame: invalid netstring length %q: %w", lenBuf, err)
+	}
+	max := c.MaxFrameSize
+	if max == 0 {
+		max = defaultMaxFrameSize
+	}
+	if n < 0 || n > max {
+		return nil, ErrFrameTooLarge
+	}
+
+	buf, err := readBounded(r, uint64(n)+1, uint64(max)+1) // +1 for the trailing comma
+	if err != nil {
+		return nil, err
+	}
+	if buf[n] != ',' {
+		return nil, errors.New("frame: netstring missing trailing comma")
+	}
+	return buf[:n], nil
+}
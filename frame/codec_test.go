@@ -0,0 +1,40 @@
+package frame
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// TestNetstringCodecBoundsLengthPrefixScan verifies that ReadFrame does not
+// buffer an unbounded number of bytes while scanning for the ':' that ends a
+// netstring's length prefix -- a peer that never sends one must not be able
+// to exhaust memory.
+func TestNetstringCodecBoundsLengthPrefixScan(t *testing.T) {
+	digits := bytes.Repeat([]byte("9"), maxNetstringLenDigits+1)
+	r := bufio.NewReader(bytes.NewReader(digits))
+
+	_, err := NetstringCodec{}.ReadFrame(r)
+	if !errors.Is(err, ErrFrameTooLarge) {
+		t.Fatalf("ReadFrame() error = %v, want ErrFrameTooLarge", err)
+	}
+}
+
+// TestNetstringCodecRoundTrip is a sanity check that ordinary frames still
+// decode correctly after bounding the length-prefix scan.
+func TestNetstringCodecRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	codec := NetstringCodec{}
+	if err := codec.WriteFrame(&buf, []byte("hello")); err != nil {
+		t.Fatalf("WriteFrame() error: %v", err)
+	}
+
+	got, err := codec.ReadFrame(bufio.NewReader(&buf))
+	if err != nil {
+		t.Fatalf("ReadFrame() error: %v", err)
+	}
+	if string(got) != "hello" {
+		t.Fatalf("ReadFrame() = %q, want %q", got, "hello")
+	}
+}
@@ -0,0 +1,131 @@
+// Package frame layers length-prefix and delimiter message framing on top of
+// gonet.AutoReconnectConn, so callers don't have to re-wrap a bufio.Reader
+// themselves, and lose any buffered partial frame, after every reconnect.
+package frame
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"sync"
+
+	"gonet"
+)
+
+// FramedConn wraps a *gonet.AutoReconnectConn and frames messages with a
+// Codec. Its framing state is reset on every reconnect, so a frame that was
+// only partially read off the old underlying connection is discarded rather
+// than corrupting the next one read off the new connection.
+type FramedConn struct {
+	conn  *gonet.AutoReconnectConn
+	codec Codec
+
+	mu      sync.Mutex
+	r       *bufio.Reader
+	gen     int
+	pending []byte
+
+	onConnected func(net.Conn) error
+}
+
+// New wraps conn, framing Read/Write traffic with codec. onConnected, if not
+// nil, runs once per reconnection -- in place of whatever the caller would
+// otherwise pass to gonet.WithOnConnected -- before any queued frame
+// read/write resumes, so protocol handshakes (auth, hello) still happen
+// first on every reconnect. New does not run it for the connection conn
+// already holds.
+func New(conn *gonet.AutoReconnectConn, codec Codec, onConnected func(net.Conn) error) *FramedConn {
+	f := &FramedConn{conn: conn, codec: codec, onConnected: onConnected}
+	f.r = bufio.NewReader(&generationReader{f: f, gen: f.gen})
+	conn.OnConnected = f.onReconnected
+	return f
+}
+
+func (f *FramedConn) onReconnected(c net.Conn) error {
+	if f.onConnected != nil {
+		if err := f.onConnected(c); err != nil {
+			return err
+		}
+	}
+
+	f.mu.Lock()
+	f.gen++
+	f.r = bufio.NewReader(&generationReader{f: f, gen: f.gen})
+	f.mu.Unlock()
+	return nil
+}
+
+// generationReader reads from the FramedConn's AutoReconnectConn, but treats
+// a reconnect that happens mid-Read as a failure of that Read rather than
+// letting the bytes it returned -- which came from the new underlying
+// connection -- get appended after whatever partial frame the bufio.Reader
+// had already buffered from the old one. AutoReconnectConn.Read retries
+// internally on reconnect, so those bytes are genuine data belonging to the
+// new connection; rather than drop them, a stale generationReader stashes
+// them on pending for the reader that replaces it to pick up first.
+type generationReader struct {
+	f   *FramedConn
+	gen int
+}
+
+func (g *generationReader) Read(p []byte) (int, error) {
+	g.f.mu.Lock()
+	if g.gen == g.f.gen && len(g.f.pending) > 0 {
+		n := copy(p, g.f.pending)
+		g.f.pending = g.f.pending[n:]
+		g.f.mu.Unlock()
+		return n, nil
+	}
+	g.f.mu.Unlock()
+
+	n, err := g.f.conn.Read(p)
+
+	g.f.mu.Lock()
+	reconnected := g.f.gen != g.gen
+	if reconnected && n > 0 {
+		g.f.pending = append(g.f.pending, p[:n]...)
+	}
+	g.f.mu.Unlock()
+	if reconnected {
+		return 0, io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+// ReadFrame reads and returns the next complete frame. If the underlying
+// connection reconnects while a frame is in flight, the partial frame is
+// discarded and ReadFrame retries against the fresh connection, again after
+// every further reconnect, instead of returning a corrupted read.
+func (f *FramedConn) ReadFrame() ([]byte, error) {
+	for {
+		f.mu.Lock()
+		r, gen := f.r, f.gen
+		f.mu.Unlock()
+
+		b, err := f.codec.ReadFrame(r)
+		if err == nil {
+			return b, nil
+		}
+		if !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, err
+		}
+
+		f.mu.Lock()
+		reconnected := f.gen != gen
+		f.mu.Unlock()
+		if !reconnected {
+			return nil, err
+		}
+	}
+}
+
+// WriteFrame encodes and writes p as a single frame.
+func (f *FramedConn) WriteFrame(p []byte) error {
+	return f.codec.WriteFrame(f.conn, p)
+}
+
+// Close closes the underlying connection.
+func (f *FramedConn) Close() error {
+	return f.conn.Close()
+}
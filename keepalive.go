@@ -0,0 +1,60 @@
+package gonet
+
+import (
+	"errors"
+	"log"
+	"net"
+	"time"
+)
+
+// ErrKeepAliveTimeout is returned internally when a keepalive probe does not
+// complete within the configured timeout.
+var ErrKeepAliveTimeout = errors.New("gonet: keepalive probe timed out")
+
+// WithKeepAlive enables an application-level heartbeat on the connection.
+// probe is invoked with the current underlying net.Conn every interval; if it
+// returns an error, or does not return at all within timeout, the connection
+// is considered broken and a reconnect is triggered proactively instead of
+// waiting for the next Read/Write to surface the failure. This closes the
+// half-open TCP gap that pure Read/Write error detection cannot see, which
+// matters for long-lived idle connections sitting behind NAT/firewalls.
+func WithKeepAlive(interval, timeout time.Duration, probe func(net.Conn) error) ConnOption {
+	return func(conn *AutoReconnectConn) {
+		conn.keepAliveInterval = interval
+		conn.keepAliveTimeout = timeout
+		conn.keepAliveProbe = probe
+	}
+}
+
+func (c *AutoReconnectConn) keepAliveLoop() {
+	ticker := time.NewTicker(c.keepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.probeOnce(); err != nil {
+				log.Printf("Keepalive probe failed, %v", err)
+				c.reconnect(err)
+			}
+		}
+	}
+}
+
+func (c *AutoReconnectConn) probeOnce() error {
+	conn := c.currentConn()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- c.keepAliveProbe(conn)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(c.keepAliveTimeout):
+		return ErrKeepAliveTimeout
+	}
+}
@@ -0,0 +1,242 @@
+package gonet
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"sync"
+	"time"
+)
+
+// ErrListenerClosed is returned by Serve once the listener has been closed
+// or shut down.
+var ErrListenerClosed = errors.New("gonet: listener closed")
+
+// acceptPollInterval bounds how long Serve can block in Accept before
+// re-checking for a pending Close/Shutdown, on listeners that support
+// SetDeadline.
+const acceptPollInterval = time.Second
+
+// deadlineListener is implemented by *net.TCPListener and *net.UnixListener,
+// letting Serve poll for shutdown via SetDeadline instead of tight-looping.
+type deadlineListener interface {
+	SetDeadline(t time.Time) error
+}
+
+// ManagedListener wraps net.Listener with graceful shutdown, a cap on
+// concurrent connections, and resilient handling of transient Accept errors,
+// giving servers the same ergonomics DialAutoReconnectContext gives clients.
+type ManagedListener struct {
+	net.Listener
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	acceptBackoff BackoffPolicy
+	sem           chan struct{}
+
+	readDeadline  time.Duration
+	writeDeadline time.Duration
+	onAccepted    func(net.Conn)
+	onClosed      func(net.Conn, error)
+}
+
+// ListenOption configures a ManagedListener.
+type ListenOption func(*ManagedListener)
+
+// WithMaxConcurrentConns bounds the number of simultaneously accepted
+// connections; Serve blocks accepting further connections (respecting
+// Close/Shutdown) once the bound is reached. n <= 0 means unlimited, matching
+// the convention WithMaxReconnectAttempts uses elsewhere in this package.
+func WithMaxConcurrentConns(n int) ListenOption {
+	return func(l *ManagedListener) {
+		if n <= 0 {
+			l.sem = nil
+			return
+		}
+		l.sem = make(chan struct{}, n)
+	}
+}
+
+var defaultAcceptBackoff BackoffPolicy = ExponentialBackoff{Base: 5 * time.Millisecond, Max: time.Second}
+
+// WithAcceptBackoff controls how Serve recovers from a temporary net.Error
+// returned by Accept. Without this option it defaults to exponential
+// doubling from 5ms capped at 1s.
+func WithAcceptBackoff(policy BackoffPolicy) ListenOption {
+	return func(l *ManagedListener) {
+		l.acceptBackoff = policy
+	}
+}
+
+// WithConnDeadlines sets the read/write deadline applied before every Read
+// and Write on each accepted connection.
+func WithConnDeadlines(read, write time.Duration) ListenOption {
+	return func(l *ManagedListener) {
+		l.readDeadline = read
+		l.writeDeadline = write
+	}
+}
+
+// WithOnAccepted registers a callback invoked with each accepted connection.
+func WithOnAccepted(fn func(net.Conn)) ListenOption {
+	return func(l *ManagedListener) {
+		l.onAccepted = fn
+	}
+}
+
+// WithOnClosed registers a callback invoked when an accepted connection is
+// closed, with the error (if any) net.Conn.Close returned.
+func WithOnClosed(fn func(net.Conn, error)) ListenOption {
+	return func(l *ManagedListener) {
+		l.onClosed = fn
+	}
+}
+
+// Listen wraps net.Listen, adding graceful shutdown via Close/Shutdown, an
+// optional cap on concurrent connections, and exponential backoff on
+// transient Accept errors instead of the tight accept-loop a bare net.Listen
+// leaves callers to write by hand.
+func Listen(network, addr string, opts ...ListenOption) (*ManagedListener, error) {
+	l, err := net.Listen(network, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	ml := &ManagedListener{Listener: l, acceptBackoff: defaultAcceptBackoff}
+	for _, opt := range opts {
+		opt(ml)
+	}
+	ml.ctx, ml.cancel = context.WithCancel(context.Background())
+	return ml, nil
+}
+
+// Serve accepts connections in a loop, wraps each with the configured
+// deadlines, and invokes handle in its own goroutine. Serve blocks until the
+// listener is closed or shut down, at which point it returns
+// ErrListenerClosed, or until Accept fails with an error that is neither a
+// temporary net.Error nor a deadline timeout, which Serve returns as is.
+func (l *ManagedListener) Serve(handle func(net.Conn)) error {
+	dl, supportsDeadline := l.Listener.(deadlineListener)
+	attempt := 0
+
+	for {
+		if l.sem != nil {
+			select {
+			case l.sem <- struct{}{}:
+			case <-l.ctx.Done():
+				return ErrListenerClosed
+			}
+		}
+
+		if supportsDeadline {
+			dl.SetDeadline(time.Now().Add(acceptPollInterval))
+		}
+
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			if l.sem != nil {
+				<-l.sem
+			}
+
+			select {
+			case <-l.ctx.Done():
+				return ErrListenerClosed
+			default:
+			}
+
+			var netErr net.Error
+			if supportsDeadline && errors.As(err, &netErr) && netErr.Timeout() {
+				attempt = 0
+				continue
+			}
+			if errors.As(err, &netErr) && netErr.Temporary() {
+				delay, _ := l.acceptBackoff.NextDelay(attempt, err)
+				attempt++
+				log.Printf("Accept temporary error, retry in %v: %v", delay, err)
+				select {
+				case <-time.After(delay):
+					continue
+				case <-l.ctx.Done():
+					return ErrListenerClosed
+				}
+			}
+			return err
+		}
+		attempt = 0
+
+		wrapped := &managedConn{Conn: conn, listener: l}
+		if l.onAccepted != nil {
+			l.onAccepted(wrapped)
+		}
+
+		l.wg.Add(1)
+		go func() {
+			defer l.wg.Done()
+			if l.sem != nil {
+				defer func() { <-l.sem }()
+			}
+			handle(wrapped)
+		}()
+	}
+}
+
+// Close stops accepting new connections immediately, without waiting for
+// already-accepted connections to finish.
+func (l *ManagedListener) Close() error {
+	l.cancel()
+	return l.Listener.Close()
+}
+
+// Shutdown stops accepting new connections and blocks until every accepted
+// connection's handler has returned, or ctx is done, whichever comes first.
+func (l *ManagedListener) Shutdown(ctx context.Context) error {
+	l.cancel()
+	if err := l.Listener.Close(); err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		l.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// managedConn wraps an accepted net.Conn with the listener's configured
+// read/write deadlines and OnClosed hook.
+type managedConn struct {
+	net.Conn
+	listener *ManagedListener
+}
+
+func (c *managedConn) Read(b []byte) (int, error) {
+	if c.listener.readDeadline > 0 {
+		c.Conn.SetReadDeadline(time.Now().Add(c.listener.readDeadline))
+	}
+	return c.Conn.Read(b)
+}
+
+func (c *managedConn) Write(b []byte) (int, error) {
+	if c.listener.writeDeadline > 0 {
+		c.Conn.SetWriteDeadline(time.Now().Add(c.listener.writeDeadline))
+	}
+	return c.Conn.Write(b)
+}
+
+func (c *managedConn) Close() error {
+	err := c.Conn.Close()
+	if c.listener.onClosed != nil {
+		c.listener.onClosed(c, err)
+	}
+	return err
+}
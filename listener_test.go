@@ -0,0 +1,36 @@
+package gonet
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+// TestMaxConcurrentConnsZeroMeansUnlimited verifies that
+// WithMaxConcurrentConns(0) does not deadlock Serve -- a zero-capacity
+// semaphore channel would block the very first Accept forever.
+func TestMaxConcurrentConnsZeroMeansUnlimited(t *testing.T) {
+	l, err := Listen("tcp", "127.0.0.1:0", WithMaxConcurrentConns(0))
+	if err != nil {
+		t.Fatalf("Listen() error: %v", err)
+	}
+	defer l.Close()
+
+	handled := make(chan struct{}, 1)
+	go l.Serve(func(conn net.Conn) {
+		conn.Close()
+		handled <- struct{}{}
+	})
+
+	client, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatalf("Dial() error: %v", err)
+	}
+	defer client.Close()
+
+	select {
+	case <-handled:
+	case <-time.After(time.Second):
+		t.Fatal("Serve never accepted a connection -- deadlocked on the zero-capacity semaphore")
+	}
+}
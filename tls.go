@@ -0,0 +1,114 @@
+package gonet
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"strings"
+)
+
+// Dialer dials a fresh connection, both for the initial connect and for
+// every subsequent reconnect attempt.
+type Dialer func(ctx context.Context, network, addr string) (net.Conn, error)
+
+var defaultDialer Dialer = func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return (&net.Dialer{}).DialContext(ctx, network, addr)
+}
+
+// WithDialer overrides how AutoReconnectConn dials. It is re-invoked on every
+// reconnect attempt, so a dialer closing over a *tls.Config sees certificate
+// or CA pool rotations applied in place, without needing to recreate the
+// AutoReconnectConn.
+func WithDialer(dial Dialer) ConnOption {
+	return func(conn *AutoReconnectConn) {
+		conn.dialer = dial
+	}
+}
+
+// WithSNIFallback supplies a list of SNI server names to try, in order, on
+// successive reconnect attempts whenever a handshake fails with a TLS alert.
+// The first dial attempt always uses tlsConfig's own ServerName unchanged;
+// fallback rotation only begins once that (or a later) handshake fails. This
+// is useful for HA endpoints and blue/green deploys that publish more than
+// one name behind the same address. Only takes effect when combined with
+// DialAutoReconnectTLSContext's default dialer; it has no effect on a dialer
+// set via WithDialer.
+func WithSNIFallback(names []string) ConnOption {
+	return func(conn *AutoReconnectConn) {
+		conn.sniFallback = names
+	}
+}
+
+// DialAutoReconnectTLSContext dials addr over TLS using tlsConfig and wraps
+// the result in an AutoReconnectConn whose reconnects re-dial over TLS with
+// the same *tls.Config, so a reloaded client certificate or refreshed CA
+// pool takes effect on the very next reconnect rather than requiring the
+// connection to be recreated. If opts include WithDialer, that dialer is used
+// for the first dial and every reconnect instead, and tlsConfig is not used.
+func DialAutoReconnectTLSContext(ctx context.Context, network, addr string, tlsConfig *tls.Config, opts ...ConnOption) (net.Conn, error) {
+	// Applied once up front so the dialer can be built before the first dial;
+	// newConn applies opts again below once the real connection exists.
+	peek := new(AutoReconnectConn)
+	for _, opt := range opts {
+		opt(peek)
+	}
+	dial := peek.dialer
+	if dial == nil {
+		dial = newTLSDialer(tlsConfig, peek.sniFallback)
+	}
+
+	conn, err := dial(ctx, network, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	autoConn := newConn(conn, network, addr, append(opts, WithDialer(dial))...)
+	if autoConn.OnConnected != nil {
+		if err := autoConn.OnConnected(conn); err != nil {
+			return nil, err
+		}
+	}
+	return autoConn, nil
+}
+
+// newTLSDialer builds a Dialer that performs a TLS handshake on every call.
+// When sniFallback is non-empty and the previous attempt's handshake failed
+// with a TLS alert, it rotates to the next name in the list before dialing
+// again.
+func newTLSDialer(tlsConfig *tls.Config, sniFallback []string) Dialer {
+	sniIndex := -1
+	failedLast := false
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		cfg := tlsConfig
+		// The primary tlsConfig, with whatever ServerName the caller set (or
+		// left empty to let Go infer it from addr), is used as-is until the
+		// first handshake failure -- only then do we start rotating through
+		// sniFallback.
+		if len(sniFallback) > 0 && failedLast {
+			sniIndex = (sniIndex + 1) % len(sniFallback)
+			cfg = tlsConfig.Clone()
+			cfg.ServerName = sniFallback[sniIndex]
+		}
+
+		conn, err := (&tls.Dialer{Config: cfg}).DialContext(ctx, network, addr)
+		failedLast = isTLSAlert(err)
+		return conn, err
+	}
+}
+
+// isTLSAlert reports whether err represents a TLS alert raised during a
+// handshake, either the peer's ("remote error: tls: ...") or ours. Apart from
+// tls.AlertError it matches on the error text, so any error whose message
+// contains "tls: " counts, including handshake failures that carry no alert.
+func isTLSAlert(err error) bool {
+	if err == nil {
+		return false
+	}
+	var alertErr tls.AlertError
+	if errors.As(err, &alertErr) {
+		return true
+	}
+	return strings.Contains(err.Error(), "tls: ")
+}
@@ -0,0 +1,51 @@
+package gonet
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// TestTLSDialerFirstAttemptUsesPrimaryServerName verifies that the first
+// dial attempt sends tlsConfig's own ServerName in the ClientHello unmodified,
+// even when sniFallback is non-empty -- fallback rotation must only start
+// after a handshake failure, not be applied unconditionally.
+func TestTLSDialerFirstAttemptUsesPrimaryServerName(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error: %v", err)
+	}
+	defer ln.Close()
+
+	gotServerName := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		srv := tls.Server(conn, &tls.Config{
+			GetConfigForClient: func(chi *tls.ClientHelloInfo) (*tls.Config, error) {
+				gotServerName <- chi.ServerName
+				return nil, errors.New("reject handshake")
+			},
+		})
+		srv.Handshake()
+	}()
+
+	tlsConfig := &tls.Config{ServerName: "primary.example.com", InsecureSkipVerify: true}
+	dial := newTLSDialer(tlsConfig, []string{"fallback-a.example.com", "fallback-b.example.com"})
+	dial(context.Background(), "tcp", ln.Addr().String())
+
+	select {
+	case got := <-gotServerName:
+		if got != "primary.example.com" {
+			t.Fatalf("first ClientHello ServerName = %q, want primary config's %q", got, "primary.example.com")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("server never observed a ClientHello")
+	}
+}
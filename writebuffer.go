@@ -0,0 +1,139 @@
+package gonet
+
+import (
+	"errors"
+	"sync"
+)
+
+// DropPolicy controls what a WithWriteBuffer-backed connection does once its
+// buffer is full.
+type DropPolicy int
+
+const (
+	// DropOldest discards the oldest buffered write to make room for the new one.
+	DropOldest DropPolicy = iota
+	// DropNewest discards the write that was about to be buffered, returning ErrBufferFull.
+	DropNewest
+	// BlockUntilSpace blocks the caller until buffered writes are flushed and space frees up.
+	BlockUntilSpace
+)
+
+// ErrBufferFull is returned by Write when DropNewest is in effect and the
+// write buffer has no room left for the attempted write. Under every policy
+// it is also returned for a single write larger than maxBytes.
+var ErrBufferFull = errors.New("gonet: write buffer full")
+
+// writeBuffer is the in-memory ring used by WithWriteBuffer to hold writes
+// issued while a reconnect is in progress.
+type writeBuffer struct {
+	mu       sync.Mutex
+	cond     *sync.Cond
+	maxBytes int
+	size     int
+	policy   DropPolicy
+	queue    [][]byte
+	flushed  chan struct{}
+}
+
+func newWriteBuffer(maxBytes int, policy DropPolicy) *writeBuffer {
+	b := &writeBuffer{maxBytes: maxBytes, policy: policy, flushed: make(chan struct{}, 1)}
+	b.cond = sync.NewCond(&b.mu)
+	return b
+}
+
+// Flushed receives a value every time the buffer successfully drains after a
+// reconnect.
+func (b *writeBuffer) Flushed() <-chan struct{} {
+	return b.flushed
+}
+
+// isEmpty reports whether the buffer currently holds no queued writes.
+func (b *writeBuffer) isEmpty() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.queue) == 0
+}
+
+func (b *writeBuffer) enqueue(p []byte) error {
+	buf := append([]byte(nil), p...)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(buf) > b.maxBytes {
+		// A single write bigger than the whole buffer can never fit, no
+		// matter how much space frees up -- waiting on b.cond would block
+		// forever under BlockUntilSpace.
+		return ErrBufferFull
+	}
+
+	for b.size+len(buf) > b.maxBytes {
+		switch b.policy {
+		case DropOldest:
+			if len(b.queue) == 0 {
+				return ErrBufferFull
+			}
+			b.size -= len(b.queue[0])
+			b.queue = b.queue[1:]
+		case BlockUntilSpace:
+			b.cond.Wait()
+		default: // DropNewest
+			return ErrBufferFull
+		}
+	}
+
+	b.queue = append(b.queue, buf)
+	b.size += len(buf)
+	return nil
+}
+
+// flush writes every buffered chunk, in order, via write. It stops at the
+// first error, leaving the unwritten remainder queued for the next attempt.
+func (b *writeBuffer) flush(write func([]byte) error) error {
+	b.mu.Lock()
+	queue := b.queue
+	b.queue = nil
+	b.size = 0
+	b.cond.Broadcast()
+	b.mu.Unlock()
+
+	for i, p := range queue {
+		if err := write(p); err != nil {
+			b.mu.Lock()
+			remaining := append([][]byte(nil), queue[i:]...)
+			b.queue = append(remaining, b.queue...)
+			for _, rest := range queue[i:] {
+				b.size += len(rest)
+			}
+			b.mu.Unlock()
+			return err
+		}
+	}
+
+	select {
+	case b.flushed <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// WithWriteBuffer makes writes issued while a reconnect is in progress get
+// enqueued to an in-memory ring buffer of at most maxBytes instead of
+// blocking the caller, and flushed in order once OnConnected succeeds. This
+// is meant for fire-and-forget sinks (telemetry, log-shipping) where
+// transient disconnects should not surface to the caller.
+func WithWriteBuffer(maxBytes int, policy DropPolicy) ConnOption {
+	return func(conn *AutoReconnectConn) {
+		conn.writeBuf = newWriteBuffer(maxBytes, policy)
+	}
+}
+
+// Flushed returns a channel that receives a value each time buffered writes
+// drain successfully after a reconnect. It returns nil if WithWriteBuffer was
+// not set.
+func (c *AutoReconnectConn) Flushed() <-chan struct{} {
+	if c.writeBuf == nil {
+		return nil
+	}
+	return c.writeBuf.Flushed()
+}
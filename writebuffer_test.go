@@ -0,0 +1,122 @@
+package gonet
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// readWithTimeout reads once from conn, failing the test instead of hanging
+// forever if nothing arrives within the given timeout.
+func readWithTimeout(t *testing.T, conn net.Conn, timeout time.Duration, want string) {
+	t.Helper()
+	buf := make([]byte, 64)
+	done := make(chan struct{})
+	var n int
+	var err error
+	go func() {
+		n, err = conn.Read(buf)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		if err != nil {
+			t.Fatalf("Read() error: %v", err)
+		}
+		if got := string(buf[:n]); got != want {
+			t.Fatalf("Read() = %q, want %q", got, want)
+		}
+	case <-time.After(timeout):
+		t.Fatalf("Read() for %q never arrived -- write stayed stranded in the buffer", want)
+	}
+}
+
+// TestWriteBufferBlockUntilSpaceOversizedWrite verifies that a single write
+// larger than the whole buffer returns ErrBufferFull immediately under
+// BlockUntilSpace instead of blocking forever -- it can never fit no matter
+// how much space frees up.
+func TestWriteBufferBlockUntilSpaceOversizedWrite(t *testing.T) {
+	b := newWriteBuffer(4, BlockUntilSpace)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- b.enqueue([]byte("way too big"))
+	}()
+
+	select {
+	case err := <-done:
+		if err != ErrBufferFull {
+			t.Fatalf("enqueue() = %v, want ErrBufferFull", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("enqueue() blocked forever on an oversized write")
+	}
+}
+
+// TestWriteBufferDrainsWriteArrivingMidFlush reproduces a bug where a Write
+// landing while reconnect() is still draining the buffer from an earlier
+// reconnect gets buffered correctly, but then never actually gets flushed --
+// it sat in the buffer until the next reconnect instead of going out once the
+// connection was already healthy, because flush() only made a single pass.
+func TestWriteBufferDrainsWriteArrivingMidFlush(t *testing.T) {
+	newClient, newServer := net.Pipe()
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return newClient, nil
+	}
+
+	initClient, initServer := net.Pipe()
+	go discard(initServer)
+
+	conn := newConn(initClient, "tcp", "fake",
+		WithDialer(dial),
+		WithWriteBuffer(1<<20, DropNewest),
+	)
+	defer conn.Close()
+
+	if err := conn.writeBuf.enqueue([]byte("first")); err != nil {
+		t.Fatalf("enqueue() error: %v", err)
+	}
+
+	reconnectDone := make(chan error, 1)
+	go func() {
+		reconnectDone <- conn.reconnect(errors.New("forced"))
+	}()
+
+	// Give reconnect() time to dial, then start flushing "first" -- its write
+	// blocks on newServer's pipe until something reads it.
+	time.Sleep(20 * time.Millisecond)
+
+	writeDone := make(chan error, 1)
+	go func() {
+		_, err := conn.Write([]byte("second"))
+		writeDone <- err
+	}()
+
+	// Give Write() time to land "second" in the buffer while "first" is still
+	// mid-flush.
+	time.Sleep(20 * time.Millisecond)
+
+	readWithTimeout(t, newServer, time.Second, "first")
+	readWithTimeout(t, newServer, time.Second, "second")
+
+	select {
+	case err := <-reconnectDone:
+		if err != nil {
+			t.Fatalf("reconnect() error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("reconnect() never returned")
+	}
+
+	select {
+	case err := <-writeDone:
+		if err != nil {
+			t.Fatalf("Write() error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Write() never returned")
+	}
+}